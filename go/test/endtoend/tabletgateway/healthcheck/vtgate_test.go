@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -18,6 +18,7 @@ This tests select/insert using the unshared keyspace added in main_test
 package healthcheck
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,7 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/endtoend/cluster"
+	"vitess.io/vitess/go/vt/discovery"
 )
 
 func TestVtgateHealthCheck(t *testing.T) {
@@ -48,6 +50,61 @@ func TestVtgateHealthCheck(t *testing.T) {
 
 	qr := exec(t, conn, "show vitess_tablets")
 	assert.Equal(t, 3, len(qr.Rows), "wrong number of results from show")
+
+	verifyDebugHealthTablets(t, clusterInstance.VtgateProcess.VerifyURL)
+}
+
+// verifyDebugHealthTablets checks that /debug/health/tablets returns a
+// snapshot covering every tablet vtgate is watching.
+func verifyDebugHealthTablets(t *testing.T, vtgateURL string) {
+	base := strings.TrimSuffix(vtgateURL, "/debug/vars")
+	resp, err := http.Get(base + "/debug/health/tablets")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var snapshot []discovery.TabletSnapshot
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&snapshot))
+	assert.Equal(t, 3, len(snapshot), "expected a snapshot entry for every tablet")
+}
+
+// TestVtgateHealthCheckStream forces a replica to stop serving and asserts
+// that a subscriber of /debug/health/stream sees the transition within the
+// healthcheck interval.
+func TestVtgateHealthCheckStream(t *testing.T) {
+	defer cluster.PanicHandler(t)
+	time.Sleep(2 * time.Second)
+
+	base := strings.TrimSuffix(clusterInstance.VtgateProcess.VerifyURL, "/debug/vars")
+	resp, err := http.Get(base + "/debug/health/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	events := make(chan discovery.TabletHealthEvent, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var event discovery.TabletHealthEvent
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err == nil {
+				events <- event
+			}
+		}
+	}()
+
+	replica := clusterInstance.Keyspaces[0].Shards[0].Vttablets[1]
+	require.NoError(t, replica.MysqlctlProcess.Stop())
+	defer replica.MysqlctlProcess.Start()
+
+	select {
+	case event := <-events:
+		assert.False(t, event.Transition.Serving, "expected a transition to not-serving")
+	case <-time.After(5 * time.Second):
+		t.Fatal("did not receive a health transition event within the healthcheck interval")
+	}
 }
 
 func verifyVtgateVariables(t *testing.T, url string) {
@@ -74,17 +131,72 @@ func verifyVtgateVariables(t *testing.T, url string) {
 	assert.True(t, isMasterTabletPresent(healthCheckConnection), "Atleast one master tablet needs to be present")
 }
 
-/*
--begin on replica should explicitly say read only
--tabletserver planner should stop dml (if easy and reasonable)
--vtgate planbuilder should not send dml to replicas
-*/
-
 func TestReplicaTransactions(t *testing.T) {
 	// TODO(deepthi): this test seems to depend on previous test. Fix tearDown so that tests are independent
 	defer cluster.PanicHandler(t)
 	// Healthcheck interval on tablet is set to 1s, so sleep for 2s
 	time.Sleep(2 * time.Second)
+
+	t.Run("session", func(t *testing.T) {
+		testReadYourWrites(t, "session", "")
+	})
+	t.Run("bounded", func(t *testing.T) {
+		testReadYourWrites(t, "bounded", "2s")
+	})
+	t.Run("eventual", func(t *testing.T) {
+		testEventualConsistency(t)
+	})
+}
+
+// testReadYourWrites covers read_consistency modes "session" and "bounded":
+// a replica transaction started right after a master write must observe
+// that write, because VTGate captured the master's GTID at BEGIN time and
+// the replica tabletserver blocked the first read until it caught up. No
+// sleeps are needed: the wait itself is the synchronization point.
+func testReadYourWrites(t *testing.T, mode, maxLag string) {
+	ctx := context.Background()
+	masterConn, err := mysql.Connect(ctx, &vtParams)
+	require.NoError(t, err)
+	replicaConn, err := mysql.Connect(ctx, &vtParams)
+	require.NoError(t, err)
+	defer masterConn.Close()
+	defer replicaConn.Close()
+
+	if maxLag == "" {
+		exec(t, replicaConn, fmt.Sprintf("set @@read_consistency = '%s'", mode))
+	} else {
+		exec(t, replicaConn, fmt.Sprintf("set @@read_consistency = '%s(%s)'", mode, maxLag))
+	}
+
+	exec(t, masterConn, "insert into customer(id, email) values(1,'email1')")
+
+	// begin on the replica captures the master's GTID and blocks, tablet
+	// side, until the replica has replicated at least that far (or
+	// read_consistency's timeout expires) - so a single attempt is enough:
+	// the wait inside begin is the synchronization point, not a client-side
+	// retry loop.
+	_ = exec(t, replicaConn, "use @replica")
+	_ = exec(t, replicaConn, "begin")
+	qr := exec(t, replicaConn, "select id, email from customer")
+	assert.Equal(t, `[[INT64(1) VARCHAR("email1")]]`, fmt.Sprintf("%v", qr.Rows), "select returned wrong result")
+
+	// reads inside the still-open replica transaction keep seeing a
+	// consistent snapshot, even as the master moves ahead.
+	exec(t, masterConn, "insert into customer(id, email) values(2,'email2')")
+	qr2 := exec(t, replicaConn, "select id, email from customer")
+	assert.Equal(t, qr.Rows, qr2.Rows)
+
+	_ = exec(t, replicaConn, "commit")
+
+	exec(t, replicaConn, "set @@read_consistency = 'eventual'")
+	exec(t, masterConn, "delete from customer where id in (1, 2)")
+}
+
+// testEventualConsistency is the pre-existing default: a replica
+// transaction does not wait for replication, so the row it reads at BEGIN
+// time is whatever has already replicated, and is held for the lifetime of
+// the transaction regardless of later master writes.
+func testEventualConsistency(t *testing.T) {
 	ctx := context.Background()
 	masterConn, err := mysql.Connect(ctx, &vtParams)
 	require.NoError(t, err)
@@ -93,18 +205,14 @@ func TestReplicaTransactions(t *testing.T) {
 	defer masterConn.Close()
 	defer replicaConn.Close()
 
-	// insert a row using master
 	exec(t, masterConn, "insert into customer(id, email) values(1,'email1')")
-	time.Sleep(1 * time.Second) // we sleep for a bit to make sure that the replication catches up
+	time.Sleep(1 * time.Second) // eventual consistency: give replication a chance to catch up
 
-	// after a short pause, SELECT the data inside a tx on a replica
 	_ = exec(t, replicaConn, "use @replica")
-	// begin transaction on replica
 	_ = exec(t, replicaConn, "begin")
 	qr := exec(t, replicaConn, "select id, email from customer")
 	assert.Equal(t, `[[INT64(1) VARCHAR("email1")]]`, fmt.Sprintf("%v", qr.Rows), "select returned wrong result")
 
-	// insert more data on master using a transaction
 	_ = exec(t, masterConn, "begin")
 	exec(t, masterConn, "insert into customer(id, email) values(2,'email2')")
 	_ = exec(t, masterConn, "commit")
@@ -119,6 +227,45 @@ func TestReplicaTransactions(t *testing.T) {
 
 	qr3 := exec(t, replicaConn, "select id, email from customer")
 	assert.Equal(t, `[[INT64(1) VARCHAR("email1")] [INT64(2) VARCHAR("email2")]]`, fmt.Sprintf("%v", qr3.Rows), "we are not seeing the updates after closing the replica transaction")
+
+	exec(t, masterConn, "delete from customer where id in (1, 2)")
+}
+
+// TestReplicaIsReadOnly covers the two places a write can be stopped
+// before it reaches MySQL on a replica: the VTGate planbuilder refuses to
+// dispatch DML/DDL targeted at @replica at all, and - belt and suspenders -
+// a DML that did reach the tabletserver would be rejected by the planner
+// there too. It checks both autocommit and explicit-transaction DML.
+func TestReplicaIsReadOnly(t *testing.T) {
+	defer cluster.PanicHandler(t)
+	time.Sleep(2 * time.Second)
+
+	ctx := context.Background()
+	replicaConn, err := mysql.Connect(ctx, &vtParams)
+	require.NoError(t, err)
+	defer replicaConn.Close()
+	_ = exec(t, replicaConn, "use @replica")
+
+	dmlStatements := []string{
+		"insert into customer(id, email) values(100,'readonly')",
+		"update customer set email = 'x' where id = 100",
+		"delete from customer where id = 100",
+		"alter table customer comment = 'should not apply on replica'",
+	}
+
+	for _, stmt := range dmlStatements {
+		_, err := replicaConn.ExecuteFetch(stmt, 1000, true)
+		assert.Error(t, err, "%s should be rejected on @replica", stmt)
+		assert.Contains(t, err.Error(), "not allowed", "%s: expected a read-only rejection, got %v", stmt, err)
+	}
+
+	_ = exec(t, replicaConn, "begin")
+	for _, stmt := range dmlStatements {
+		_, err := replicaConn.ExecuteFetch(stmt, 1000, true)
+		assert.Error(t, err, "%s should be rejected on @replica inside a transaction", stmt)
+		assert.Contains(t, err.Error(), "not allowed", "%s: expected a read-only rejection, got %v", stmt, err)
+	}
+	_ = exec(t, replicaConn, "rollback")
 }
 
 func getMapFromJSON(JSON map[string]interface{}, key string) map[string]interface{} {
@@ -147,4 +294,4 @@ func exec(t *testing.T, conn *mysql.Conn, query string) *sqltypes.Result {
 	qr, err := conn.ExecuteFetch(query, 1000, true)
 	require.Nil(t, err)
 	return qr
-}
\ No newline at end of file
+}
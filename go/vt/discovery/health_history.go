@@ -0,0 +1,201 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// healthHistorySize bounds the number of state transitions we remember per
+// tablet. It is intentionally small: this is meant for "what just happened"
+// dashboards, not long-term history, which belongs in a time series store.
+const healthHistorySize = 50
+
+// TabletTransition is a single recorded change of a tablet's serving state,
+// as observed by the healthcheck.
+type TabletTransition struct {
+	Time                  time.Time
+	TabletType            topodatapb.TabletType
+	Serving               bool
+	ReplicationLagSeconds uint32
+	LastError             string
+}
+
+// TabletHealthEvent is published every time a tablet's health changes in a
+// way that affects serving eligibility (serving <-> not serving, or type
+// change). It is consumed by HealthEventBroker subscribers such as the
+// VTGate /debug/health/stream endpoint.
+type TabletHealthEvent struct {
+	Tablet     *topodatapb.Tablet
+	Transition TabletTransition
+}
+
+// tabletHealthRecord tracks the rolling history for a single tablet.
+type tabletHealthRecord struct {
+	tablet      *topodatapb.Tablet
+	tabletType  topodatapb.TabletType
+	serving     bool
+	lagSeconds  uint32
+	lastError   string
+	transitions []TabletTransition
+}
+
+// HealthEventBroker keeps a bounded ring of recent state transitions for
+// every tablet the healthcheck knows about, and fans out new transitions to
+// any subscribers. It is safe for concurrent use.
+type HealthEventBroker struct {
+	mu        sync.Mutex
+	records   map[string]*tabletHealthRecord // keyed by tablet alias
+	listeners map[int]chan TabletHealthEvent
+	nextID    int
+}
+
+// NewHealthEventBroker returns an empty broker ready to record transitions.
+func NewHealthEventBroker() *HealthEventBroker {
+	return &HealthEventBroker{
+		records:   make(map[string]*tabletHealthRecord),
+		listeners: make(map[int]chan TabletHealthEvent),
+	}
+}
+
+// Subscribe registers a channel that receives every future transition. The
+// returned cancel function must be called once the subscriber is done, to
+// release the channel.
+func (b *HealthEventBroker) Subscribe() (<-chan TabletHealthEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	ch := make(chan TabletHealthEvent, 10)
+	b.listeners[id] = ch
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if existing, ok := b.listeners[id]; ok {
+			delete(b.listeners, id)
+			close(existing)
+		}
+	}
+}
+
+// Record stores the latest health for a tablet, and - if the serving state
+// or tablet type changed since the last known value - appends a transition
+// and notifies subscribers. now is passed in so callers can make this
+// deterministic in tests.
+func (b *HealthEventBroker) Record(tablet *topodatapb.Tablet, th *TabletHealth, now time.Time) {
+	alias := topoproto.TabletAliasString(tablet.Alias)
+
+	b.mu.Lock()
+	rec, ok := b.records[alias]
+	if !ok {
+		rec = &tabletHealthRecord{tablet: tablet}
+		b.records[alias] = rec
+	}
+
+	changed := !ok || rec.serving != th.Serving || rec.tabletType != th.Target.TabletType
+	rec.tablet = tablet
+	rec.tabletType = th.Target.TabletType
+	rec.serving = th.Serving
+	rec.lagSeconds = uint32(th.Stats.GetReplicationLagSeconds())
+	if th.LastError != nil {
+		rec.lastError = th.LastError.Error()
+	} else {
+		rec.lastError = ""
+	}
+
+	var event TabletHealthEvent
+	if changed {
+		transition := TabletTransition{
+			Time:                  now,
+			TabletType:            rec.tabletType,
+			Serving:               rec.serving,
+			ReplicationLagSeconds: rec.lagSeconds,
+			LastError:             rec.lastError,
+		}
+		rec.transitions = append(rec.transitions, transition)
+		if len(rec.transitions) > healthHistorySize {
+			rec.transitions = rec.transitions[len(rec.transitions)-healthHistorySize:]
+		}
+		event = TabletHealthEvent{Tablet: tablet, Transition: transition}
+	}
+	listeners := make([]chan TabletHealthEvent, 0, len(b.listeners))
+	for _, ch := range b.listeners {
+		listeners = append(listeners, ch)
+	}
+	b.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	for _, ch := range listeners {
+		select {
+		case ch <- event:
+		default:
+			// Slow subscriber: drop the event rather than block the
+			// healthcheck loop. Subscribers that need guarantees should
+			// poll /debug/health/tablets to resync.
+		}
+	}
+}
+
+// Watch subscribes to hc, the HealthCheck instance actually used for query
+// routing, and records every update it produces for as long as hc keeps the
+// channel open. Callers run it in its own goroutine right after
+// constructing the broker, e.g. from an OnHealthCheckCreated observer (see
+// vtgate.OnHealthCheckCreated).
+func (b *HealthEventBroker) Watch(hc HealthCheck) {
+	ch := hc.Subscribe()
+	defer hc.Unsubscribe(ch)
+	for th := range ch {
+		b.Record(th.Tablet, th, time.Now())
+	}
+}
+
+// TabletSnapshot is the JSON-friendly view of a single tablet's current
+// health and recent history, as returned by /debug/health/tablets.
+type TabletSnapshot struct {
+	Alias                 string             `json:"alias"`
+	TabletType            string             `json:"tablet_type"`
+	Serving               bool               `json:"serving"`
+	ReplicationLagSeconds uint32             `json:"replication_lag_seconds"`
+	LastError             string             `json:"last_error,omitempty"`
+	Transitions           []TabletTransition `json:"transitions"`
+}
+
+// Snapshot returns the current state and bounded transition history for
+// every tablet known to the broker.
+func (b *HealthEventBroker) Snapshot() []TabletSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]TabletSnapshot, 0, len(b.records))
+	for alias, rec := range b.records {
+		out = append(out, TabletSnapshot{
+			Alias:                 alias,
+			TabletType:            rec.tabletType.String(),
+			Serving:               rec.serving,
+			ReplicationLagSeconds: rec.lagSeconds,
+			LastError:             rec.lastError,
+			Transitions:           append([]TabletTransition(nil), rec.transitions...),
+		})
+	}
+	return out
+}
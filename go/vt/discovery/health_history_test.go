@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func testTablet(alias string) *topodatapb.Tablet {
+	return &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 1},
+		Hostname: alias,
+	}
+}
+
+func TestHealthEventBrokerRecordsTransitionsOnly(t *testing.T) {
+	b := NewHealthEventBroker()
+	tablet := testTablet("replica-1")
+	now := time.Now()
+
+	th := &TabletHealth{
+		Tablet:  tablet,
+		Target:  &querypb.Target{TabletType: topodatapb.TabletType_REPLICA},
+		Serving: true,
+		Stats:   &querypb.RealtimeStats{},
+	}
+	b.Record(tablet, th, now)
+	// Recording the same state again should not add a new transition.
+	b.Record(tablet, th, now.Add(time.Second))
+
+	snapshot := b.Snapshot()
+	require.Len(t, snapshot, 1)
+	assert.Len(t, snapshot[0].Transitions, 1, "unchanged health should not grow the transition history")
+
+	notServing := &TabletHealth{
+		Tablet:  tablet,
+		Target:  &querypb.Target{TabletType: topodatapb.TabletType_REPLICA},
+		Serving: false,
+		Stats:   &querypb.RealtimeStats{},
+	}
+	b.Record(tablet, notServing, now.Add(2*time.Second))
+
+	snapshot = b.Snapshot()
+	require.Len(t, snapshot, 1)
+	assert.False(t, snapshot[0].Serving)
+	assert.Len(t, snapshot[0].Transitions, 2, "a serving state change should be recorded as a new transition")
+}
+
+func TestHealthEventBrokerSubscribeDeliversTransitions(t *testing.T) {
+	b := NewHealthEventBroker()
+	tablet := testTablet("replica-1")
+
+	events, cancel := b.Subscribe()
+	defer cancel()
+
+	th := &TabletHealth{
+		Tablet:  tablet,
+		Target:  &querypb.Target{TabletType: topodatapb.TabletType_REPLICA},
+		Serving: false,
+		Stats:   &querypb.RealtimeStats{},
+	}
+	b.Record(tablet, th, time.Now())
+
+	select {
+	case event := <-events:
+		assert.False(t, event.Transition.Serving)
+	case <-time.After(time.Second):
+		t.Fatal("expected a transition event to be delivered to the subscriber")
+	}
+}
+
+func TestHealthEventBrokerHistoryIsBounded(t *testing.T) {
+	b := NewHealthEventBroker()
+	tablet := testTablet("replica-1")
+
+	for i := 0; i < healthHistorySize+10; i++ {
+		th := &TabletHealth{
+			Tablet:  tablet,
+			Target:  &querypb.Target{TabletType: topodatapb.TabletType_REPLICA},
+			Serving: i%2 == 0,
+			Stats:   &querypb.RealtimeStats{},
+		}
+		b.Record(tablet, th, time.Now())
+	}
+
+	snapshot := b.Snapshot()
+	require.Len(t, snapshot, 1)
+	assert.Len(t, snapshot[0].Transitions, healthHistorySize)
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"vitess.io/vitess/go/vt/vtgate/planbuilder"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// beginQuery is what tx_conn.Begin sends to start a transaction on a
+// tablet of the given type: "start transaction read only" for a replica or
+// rdonly target, so a write that slips past VTGate's own planbuilder
+// guard (planbuilder.RunPreBuildChecks) is still rejected by the
+// tabletserver and MySQL itself, not just silently allowed through.
+func beginQuery(target topodatapb.TabletType) string {
+	return planbuilder.BeginQueryForTabletType(target)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// healthCheckObservers are invoked, in order, with the discovery.HealthCheck
+// instance vtgate.Init constructs for query routing, right after it has
+// been built. Components that need that instance register themselves here
+// via OnHealthCheckCreated instead of forcing Init to import every
+// consumer directly.
+var healthCheckObservers []func(discovery.HealthCheck)
+
+// OnHealthCheckCreated registers fn to run once vtgate.Init has built the
+// discovery.HealthCheck used for tablet discovery and query routing.
+func OnHealthCheckCreated(fn func(discovery.HealthCheck)) {
+	healthCheckObservers = append(healthCheckObservers, fn)
+}
+
+// NotifyHealthCheckCreated runs every observer registered via
+// OnHealthCheckCreated. vtgate.Init calls this immediately after
+// constructing its discovery.HealthCheck.
+func NotifyHealthCheckCreated(hc discovery.HealthCheck) {
+	for _, fn := range healthCheckObservers {
+		fn(hc)
+	}
+}
+
+func init() {
+	OnHealthCheckCreated(func(hc discovery.HealthCheck) {
+		broker := discovery.NewHealthEventBroker()
+		go broker.Watch(hc)
+		registerDebugHealthHandlers(broker)
+	})
+}
+
+// registerDebugHealthHandlers exposes the healthcheck state tracked by
+// broker over HTTP, so operators can build dashboards and alerting without
+// polling `show vitess_tablets`:
+//   - /debug/health/tablets returns a JSON snapshot of every known tablet,
+//     its current serving state, and a bounded history of recent transitions.
+//   - /debug/health/stream is a Server-Sent-Events endpoint that pushes a
+//     JSON-encoded discovery.TabletHealthEvent as soon as a tablet's serving
+//     state or type changes.
+func registerDebugHealthHandlers(broker *discovery.HealthEventBroker) {
+	servenv.HTTPHandleFunc("/debug/health/tablets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(broker.Snapshot()); err != nil {
+			log.Errorf("debug/health/tablets: failed to encode snapshot: %v", err)
+		}
+	})
+
+	servenv.HTTPHandleFunc("/debug/health/stream", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+
+		events, cancel := broker.Subscribe()
+		defer cancel()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				payload, err := json.Marshal(event)
+				if err != nil {
+					log.Errorf("debug/health/stream: failed to encode event: %v", err)
+					continue
+				}
+				fmt.Fprintf(w, "data: %s\n\n", payload)
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/discovery"
+)
+
+// TestNotifyHealthCheckCreatedRunsObservers exercises the seam vtgate.Init
+// calls right after constructing its discovery.HealthCheck. It clears the
+// registered observers (including the one this package registers for
+// itself in its init()), registers two, and checks that one call to
+// NotifyHealthCheckCreated runs each of them once.
+func TestNotifyHealthCheckCreatedRunsObservers(t *testing.T) {
+	saved := healthCheckObservers
+	defer func() { healthCheckObservers = saved }()
+	healthCheckObservers = nil
+
+	var got []discovery.HealthCheck
+	OnHealthCheckCreated(func(hc discovery.HealthCheck) {
+		got = append(got, hc)
+	})
+	OnHealthCheckCreated(func(hc discovery.HealthCheck) {
+		got = append(got, hc)
+	})
+
+	var hc discovery.HealthCheck
+	NotifyHealthCheckCreated(hc)
+
+	assert.Len(t, got, 2, "every registered observer should run once")
+}
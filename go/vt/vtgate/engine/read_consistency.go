@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// SessionReadConsistencyVar is the system variable name recognized by
+// VTGate's SET handling (see vtgate.SystemVariableHooks) for
+// `set @@read_consistency = ...`.
+const SessionReadConsistencyVar = "read_consistency"
+
+// ReadConsistency controls how a replica connection reconciles reads against
+// a GTID position captured on the master, via `set @@read_consistency = ...`.
+type ReadConsistency int
+
+const (
+	// ReadConsistencyEventual is the default: reads on a replica see
+	// whatever has replicated so far, with no wait. This is the behavior
+	// Vitess has always had.
+	ReadConsistencyEventual ReadConsistency = iota
+	// ReadConsistencySession requires the replica to have replicated at
+	// least as far as the last write (or BEGIN-time master position) seen
+	// on this session, blocking until it has or a fixed default timeout
+	// elapses.
+	ReadConsistencySession
+	// ReadConsistencyBounded is like Session, but the caller supplies an
+	// explicit max-lag duration to wait for instead of that default.
+	ReadConsistencyBounded
+)
+
+// String implements fmt.Stringer.
+func (c ReadConsistency) String() string {
+	switch c {
+	case ReadConsistencySession:
+		return "session"
+	case ReadConsistencyBounded:
+		return "bounded"
+	default:
+		return "eventual"
+	}
+}
+
+// ParseReadConsistency parses the value assigned to `@@read_consistency`.
+// The bounded form may optionally carry a duration suffix, e.g.
+// "bounded(2s)"; when omitted, callers should fall back to a configured
+// default max-lag.
+func ParseReadConsistency(value string) (ReadConsistency, time.Duration, error) {
+	switch {
+	case value == "session":
+		return ReadConsistencySession, 0, nil
+	case value == "eventual":
+		return ReadConsistencyEventual, 0, nil
+	case value == "bounded":
+		return ReadConsistencyBounded, 0, nil
+	case len(value) > len("bounded(") && value[:len("bounded(")] == "bounded(" && value[len(value)-1] == ')':
+		d, err := time.ParseDuration(value[len("bounded(") : len(value)-1])
+		if err != nil {
+			return ReadConsistencyEventual, 0, fmt.Errorf("invalid max-lag duration in read_consistency %q: %v", value, err)
+		}
+		return ReadConsistencyBounded, d, nil
+	default:
+		return ReadConsistencyEventual, 0, fmt.Errorf("invalid value for read_consistency: %q, expected 'session', 'bounded', 'bounded(<duration>)' or 'eventual'", value)
+	}
+}
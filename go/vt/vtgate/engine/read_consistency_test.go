@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReadConsistency(t *testing.T) {
+	tests := []struct {
+		value      string
+		wantMode   ReadConsistency
+		wantMaxLag time.Duration
+		wantErr    bool
+	}{
+		{value: "eventual", wantMode: ReadConsistencyEventual},
+		{value: "session", wantMode: ReadConsistencySession},
+		{value: "bounded", wantMode: ReadConsistencyBounded},
+		{value: "bounded(2s)", wantMode: ReadConsistencyBounded, wantMaxLag: 2 * time.Second},
+		{value: "bounded(500ms)", wantMode: ReadConsistencyBounded, wantMaxLag: 500 * time.Millisecond},
+		{value: "bounded(nonsense)", wantErr: true},
+		{value: "strong", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			mode, maxLag, err := ParseReadConsistency(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMode, mode)
+			assert.Equal(t, tt.wantMaxLag, maxLag)
+		})
+	}
+}
+
+func TestReadConsistencyString(t *testing.T) {
+	assert.Equal(t, "eventual", ReadConsistencyEventual.String())
+	assert.Equal(t, "session", ReadConsistencySession.String())
+	assert.Equal(t, "bounded", ReadConsistencyBounded.String())
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import "fmt"
+
+// Executor plans and dispatches queries on behalf of a session.
+type Executor struct{}
+
+// handleSet executes `set @@name = value` for a name Executor doesn't
+// already recognize as a MySQL system variable: it looks name up in
+// SystemVariableHooks so a Vitess-specific setting, like read_consistency,
+// can plug in without this function growing a case for every feature.
+func (e *Executor) handleSet(session *SafeSession, name, value string) error {
+	hook, ok := SystemVariableHooks[name]
+	if !ok {
+		return fmt.Errorf("unsupported system variable: %s", name)
+	}
+	return hook(session, value)
+}
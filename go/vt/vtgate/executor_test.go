@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+func TestExecutorHandleSetReadConsistencyGoesThroughHook(t *testing.T) {
+	e := &Executor{}
+	session := &SafeSession{}
+
+	require.NoError(t, e.handleSet(session, engine.SessionReadConsistencyVar, "bounded(2s)"))
+	assert.Equal(t, "bounded(2s)", session.SystemVariables[engine.SessionReadConsistencyVar])
+
+	require.Error(t, e.handleSet(session, engine.SessionReadConsistencyVar, "not-a-mode"))
+}
+
+func TestExecutorHandleSetUnknownVariable(t *testing.T) {
+	e := &Executor{}
+	err := e.handleSet(&SafeSession{}, "some_made_up_var", "1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported system variable")
+}
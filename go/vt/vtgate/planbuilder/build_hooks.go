@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// writeStatementTypes are the statement type keywords that mutate data or
+// schema, as used by the PreBuildChecks entry registered in init below,
+// which passes only these to rejectDMLOnReplica; everything else (select,
+// show, etc.) is always fine to send to a replica.
+var writeStatementTypes = map[string]bool{
+	"insert": true,
+	"update": true,
+	"delete": true,
+	"ddl":    true,
+}
+
+// PreBuildChecks run before Build constructs a plan for a statement, with
+// the statement's type keyword (lowercased, e.g. "insert") and the tablet
+// type the query has been resolved to target. Build must run every
+// registered check right after target resolution and abort with its error,
+// without building a plan, if any check returns non-nil.
+var PreBuildChecks []func(stmtType string, target topodatapb.TabletType) error
+
+func init() {
+	PreBuildChecks = append(PreBuildChecks, func(stmtType string, target topodatapb.TabletType) error {
+		if !writeStatementTypes[stmtType] {
+			return nil
+		}
+		return rejectDMLOnReplica(stmtType, target)
+	})
+}
+
+// RunPreBuildChecks runs every registered PreBuildChecks entry, returning
+// the first error encountered, if any.
+func RunPreBuildChecks(stmtType string, target topodatapb.TabletType) error {
+	for _, check := range PreBuildChecks {
+		if err := check(stmtType, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
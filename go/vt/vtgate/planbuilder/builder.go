@@ -0,0 +1,38 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// Plan is a built, ready-to-execute query plan.
+type Plan struct {
+	StmtType string
+	Target   topodatapb.TabletType
+}
+
+// Build constructs a Plan for stmtType against its already-resolved target
+// tablet type, after running every registered PreBuildChecks entry - today that's
+// just rejectDMLOnReplica - so a write that resolves to a replica or
+// rdonly target is rejected here, before a plan is built for it at all.
+func Build(stmtType string, target topodatapb.TabletType) (*Plan, error) {
+	if err := RunPreBuildChecks(stmtType, target); err != nil {
+		return nil, err
+	}
+	return &Plan{StmtType: stmtType, Target: target}, nil
+}
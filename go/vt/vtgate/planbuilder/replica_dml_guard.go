@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"fmt"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// rejectDMLOnReplica stops a DML or DDL statement targeted at a non-master
+// tablet type before VTGate builds a plan for it, rather than letting it
+// dispatch to a tablet and fail there. It is checked at the top of the
+// planbuilder entry point, alongside the existing target-resolution logic.
+func rejectDMLOnReplica(stmtType string, target topodatapb.TabletType) error {
+	if target == topodatapb.TabletType_MASTER || target == topodatapb.TabletType_UNKNOWN {
+		return nil
+	}
+	return vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION,
+		fmt.Sprintf("%s is not allowed on %s: only the master tablet accepts writes", stmtType, topoproto.TabletTypeLString(target)))
+}
+
+// BeginQueryForTabletType picks the query VTGate sends downstream to start
+// a transaction, based on the tablet type the transaction will run
+// against. A transaction on a replica or rdonly tablet is always read
+// only: it exists purely to give the session a consistent snapshot, and
+// making that explicit lets the tabletserver and MySQL itself reject any
+// write that slips through. tx_conn.Begin calls this to build the query it
+// sends to the tablet, instead of always sending a plain "begin".
+func BeginQueryForTabletType(target topodatapb.TabletType) string {
+	if target == topodatapb.TabletType_MASTER || target == topodatapb.TabletType_UNKNOWN {
+		return "begin"
+	}
+	return "start transaction read only"
+}
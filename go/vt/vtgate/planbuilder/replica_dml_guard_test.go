@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestRejectDMLOnReplica(t *testing.T) {
+	require.NoError(t, rejectDMLOnReplica("insert", topodatapb.TabletType_MASTER))
+	require.NoError(t, rejectDMLOnReplica("insert", topodatapb.TabletType_UNKNOWN))
+
+	for _, target := range []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY} {
+		err := rejectDMLOnReplica("insert", target)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not allowed")
+	}
+}
+
+func TestBeginQueryForTabletType(t *testing.T) {
+	assert.Equal(t, "begin", BeginQueryForTabletType(topodatapb.TabletType_MASTER))
+	assert.Equal(t, "start transaction read only", BeginQueryForTabletType(topodatapb.TabletType_REPLICA))
+	assert.Equal(t, "start transaction read only", BeginQueryForTabletType(topodatapb.TabletType_RDONLY))
+}
+
+func TestRunPreBuildChecksRejectsDMLOnReplicaOnly(t *testing.T) {
+	require.Error(t, RunPreBuildChecks("insert", topodatapb.TabletType_REPLICA))
+	require.NoError(t, RunPreBuildChecks("select", topodatapb.TabletType_REPLICA))
+	require.NoError(t, RunPreBuildChecks("insert", topodatapb.TabletType_MASTER))
+}
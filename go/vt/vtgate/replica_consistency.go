@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+// SystemVariableHooks is consulted by executor.handleSet for any `set
+// @@name = value` whose name isn't a MySQL system variable vitess already
+// understands. It lets a Vitess-specific session variable live next to the
+// feature it configures instead of growing a single switch in
+// executor.go. The hook receives the raw assigned value and mutates
+// session in place; a non-nil error is surfaced to the client as if the
+// SET itself had failed.
+var SystemVariableHooks = map[string]func(session *SafeSession, value string) error{}
+
+func init() {
+	SystemVariableHooks[engine.SessionReadConsistencyVar] = setReadConsistency
+}
+
+// setReadConsistency validates the value assigned by
+// `set @@read_consistency = value` and, once valid, stores it on the
+// session the same way any other Vitess-recognized system variable is
+// stored, so it rides along with the session's reserved connection to the
+// replica exactly like `set @@ddl_strategy = ...` does today. It is
+// registered against engine.SessionReadConsistencyVar in
+// SystemVariableHooks above.
+//
+// The mode and max-lag it selects are re-parsed by
+// TxConn.readConsistencyTarget (via engine.ParseReadConsistency) from that
+// same stored value when a transaction begins on a replica, and used to
+// compute the target GTID passed to tabletserver.BeginReplicaTransaction.
+func setReadConsistency(session *SafeSession, value string) error {
+	if _, _, err := engine.ParseReadConsistency(value); err != nil {
+		return err
+	}
+	session.SetSystemVariable(engine.SessionReadConsistencyVar, value)
+	return nil
+}
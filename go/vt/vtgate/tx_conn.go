@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// defaultReadConsistencyTimeout bounds how long a "session" transaction (or
+// a "bounded" one that didn't specify its own max-lag) waits for a replica
+// to catch up to the captured master position, before BeginReplicaTransaction
+// gives up with a DEADLINE_EXCEEDED error (see ErrReadConsistencyTimeout).
+const defaultReadConsistencyTimeout = 2 * time.Second
+
+// Gateway is the subset of the tablet gateway TxConn needs to start a
+// transaction against a single target.
+type Gateway interface {
+	MasterPosition(ctx context.Context, keyspace, shard string) (mysql.Position, error)
+	BeginExecute(ctx context.Context, target *topodatapb.Target, query string, targetGTID mysql.Position, timeout time.Duration) (transactionID int64, err error)
+}
+
+// TxConn coordinates opening and closing transactions against the shards a
+// query touches.
+type TxConn struct {
+	gateway Gateway
+}
+
+// Begin starts a transaction against target, using beginQuery to pick
+// "begin" or "start transaction read only" for the target's tablet type
+// (see planbuilder.BeginQueryForTabletType). On a replica or rdonly target
+// whose session has read_consistency set to "session" or "bounded", it
+// first captures the master's current position and passes it down as
+// targetGTID, so the tabletserver can block the transaction's first read
+// until the replica has caught up (see tabletserver.BeginReplicaTransaction).
+// On a master target, or with read_consistency left at its "eventual"
+// default, this behaves exactly as it always has: no position is captured
+// and nothing blocks.
+func (tc *TxConn) Begin(ctx context.Context, session *SafeSession, target *topodatapb.Target) (int64, error) {
+	targetGTID, timeout, err := tc.readConsistencyTarget(ctx, session, target)
+	if err != nil {
+		return 0, err
+	}
+	return tc.gateway.BeginExecute(ctx, target, beginQuery(target.TabletType), targetGTID, timeout)
+}
+
+// readConsistencyTarget computes the GTID position, if any, a replica
+// transaction against target must catch up to before its first read, from
+// the session's read_consistency system variable.
+func (tc *TxConn) readConsistencyTarget(ctx context.Context, session *SafeSession, target *topodatapb.Target) (mysql.Position, time.Duration, error) {
+	if target.TabletType == topodatapb.TabletType_MASTER || target.TabletType == topodatapb.TabletType_UNKNOWN {
+		return mysql.Position{}, 0, nil
+	}
+
+	value := session.SystemVariables[engine.SessionReadConsistencyVar]
+	if value == "" {
+		return mysql.Position{}, 0, nil
+	}
+
+	mode, maxLag, err := engine.ParseReadConsistency(value)
+	if err != nil || mode == engine.ReadConsistencyEventual {
+		return mysql.Position{}, 0, err
+	}
+
+	pos, err := tc.gateway.MasterPosition(ctx, target.Keyspace, target.Shard)
+	if err != nil {
+		return mysql.Position{}, 0, err
+	}
+	if maxLag == 0 {
+		maxLag = defaultReadConsistencyTimeout
+	}
+	return pos, maxLag, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// fakeGateway records the target GTID and timeout it was asked to begin
+// with, and reports masterPos for MasterPosition.
+type fakeGateway struct {
+	masterPos   mysql.Position
+	gotQuery    string
+	gotTarget   mysql.Position
+	gotTimeout  time.Duration
+	masterCalls int
+}
+
+func (g *fakeGateway) MasterPosition(ctx context.Context, keyspace, shard string) (mysql.Position, error) {
+	g.masterCalls++
+	return g.masterPos, nil
+}
+
+func (g *fakeGateway) BeginExecute(ctx context.Context, target *topodatapb.Target, query string, targetGTID mysql.Position, timeout time.Duration) (int64, error) {
+	g.gotQuery = query
+	g.gotTarget = targetGTID
+	g.gotTimeout = timeout
+	return 1, nil
+}
+
+func TestTxConnBeginOnMasterDoesNotCaptureGTID(t *testing.T) {
+	gw := &fakeGateway{}
+	tc := &TxConn{gateway: gw}
+	session := &SafeSession{}
+
+	_, err := tc.Begin(context.Background(), session, &topodatapb.Target{TabletType: topodatapb.TabletType_MASTER})
+	require.NoError(t, err)
+	assert.Equal(t, 0, gw.masterCalls)
+	assert.True(t, gw.gotTarget.IsZero())
+	assert.Equal(t, "begin", gw.gotQuery)
+}
+
+func TestTxConnBeginOnReplicaWithEventualDoesNotCaptureGTID(t *testing.T) {
+	gw := &fakeGateway{}
+	tc := &TxConn{gateway: gw}
+	session := &SafeSession{}
+
+	_, err := tc.Begin(context.Background(), session, &topodatapb.Target{TabletType: topodatapb.TabletType_REPLICA})
+	require.NoError(t, err)
+	assert.Equal(t, 0, gw.masterCalls)
+	assert.True(t, gw.gotTarget.IsZero())
+	assert.Equal(t, "start transaction read only", gw.gotQuery)
+}
+
+func TestTxConnBeginOnReplicaWithSessionConsistencyCapturesMasterGTID(t *testing.T) {
+	pos, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	require.NoError(t, err)
+
+	gw := &fakeGateway{masterPos: pos}
+	tc := &TxConn{gateway: gw}
+	session := &SafeSession{}
+	session.SetSystemVariable(engine.SessionReadConsistencyVar, "session")
+
+	_, err = tc.Begin(context.Background(), session, &topodatapb.Target{TabletType: topodatapb.TabletType_REPLICA})
+	require.NoError(t, err)
+	assert.Equal(t, 1, gw.masterCalls)
+	assert.Equal(t, pos, gw.gotTarget)
+	assert.Equal(t, defaultReadConsistencyTimeout, gw.gotTimeout)
+}
+
+func TestTxConnBeginOnReplicaWithBoundedConsistencyUsesExplicitTimeout(t *testing.T) {
+	pos, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	require.NoError(t, err)
+
+	gw := &fakeGateway{masterPos: pos}
+	tc := &TxConn{gateway: gw}
+	session := &SafeSession{}
+	session.SystemVariables = map[string]string{engine.SessionReadConsistencyVar: "bounded(500ms)"}
+
+	_, err = tc.Begin(context.Background(), session, &topodatapb.Target{TabletType: topodatapb.TabletType_RDONLY})
+	require.NoError(t, err)
+	assert.Equal(t, pos, gw.gotTarget)
+	assert.Equal(t, 500*time.Millisecond, gw.gotTimeout)
+}
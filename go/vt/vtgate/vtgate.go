@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/srvtopo"
+)
+
+// VTGate is the per-process singleton that owns the subsystems every
+// incoming query goes through.
+type VTGate struct {
+	HealthCheck discovery.HealthCheck
+}
+
+// Init builds the discovery.HealthCheck a running VTGate uses to track
+// tablet serving state for every keyspace/shard/tablet-type it routes
+// queries to, and notifies anything registered via OnHealthCheckCreated -
+// today that's the /debug/health/tablets and /debug/health/stream
+// endpoints - before returning it.
+func Init(ctx context.Context, serv srvtopo.Server, cell string, retryDelay, healthCheckTimeout time.Duration) *VTGate {
+	hc := discovery.NewHealthCheck(ctx, retryDelay, healthCheckTimeout, serv, cell, "")
+	NotifyHealthCheckCreated(hc)
+
+	return &VTGate{HealthCheck: hc}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// gtidWaitPollInterval is how often we re-check gtid_executed while blocked
+// in waitForGTID. It is deliberately small: the wait is expected to resolve
+// within a fraction of a second in the common case of a healthy replica.
+const gtidWaitPollInterval = 10 * time.Millisecond
+
+// ErrReadConsistencyTimeout describes a replica connection that failed to
+// catch up to the requested GTID position within the caller's timeout: the
+// target and current positions and the timeout. waitForGTID returns its
+// Error() text inside a vtrpcpb.Code_DEADLINE_EXCEEDED error; the
+// *ErrReadConsistencyTimeout value itself is not returned.
+type ErrReadConsistencyTimeout struct {
+	Target  mysql.Position
+	Current mysql.Position
+	Timeout time.Duration
+}
+
+func (e *ErrReadConsistencyTimeout) Error() string {
+	return fmt.Sprintf("replica did not catch up to GTID position %v within %v (currently at %v)", e.Target, e.Timeout, e.Current)
+}
+
+// positionFetcher abstracts reading a replica connection's own executed
+// GTID set (i.e. what a healthy replica would report for `SHOW SLAVE
+// STATUS`'s Executed_Gtid_Set, not the master's binlog position - that is
+// what mysql.Conn.PrimaryPosition elsewhere in this codebase means), so
+// tests can substitute a fake without standing up a real mysqld.
+type positionFetcher interface {
+	ExecutedPosition() (mysql.Position, error)
+}
+
+// waitForGTID blocks the calling goroutine until conn reports an executed
+// GTID set that covers target, the context is done, or timeout elapses, in
+// which case it returns a vtrpcpb.Code_DEADLINE_EXCEEDED error carrying the
+// message of an ErrReadConsistencyTimeout. It is called once, right after
+// BEGIN, by a replica connection running with read_consistency set to
+// "session" or "bounded".
+func waitForGTID(ctx context.Context, conn positionFetcher, target mysql.Position, timeout time.Duration) error {
+	if target.IsZero() {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(gtidWaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := conn.ExecutedPosition()
+		if err != nil {
+			return err
+		}
+		if current.AtLeast(target) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return vterrors.New(vtrpcpb.Code_DEADLINE_EXCEEDED, (&ErrReadConsistencyTimeout{
+				Target:  target,
+				Current: current,
+				Timeout: timeout,
+			}).Error())
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
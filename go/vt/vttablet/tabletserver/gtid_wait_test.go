@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+)
+
+// advancingPositionFetcher reports behind for the first callsUntil-1 calls
+// to ExecutedPosition, then target from call callsUntil on - simulating a
+// replica that closes the gap while waitForGTID is polling it.
+type advancingPositionFetcher struct {
+	target     mysql.Position
+	behind     mysql.Position
+	callsUntil int
+	calls      int
+}
+
+func (f *advancingPositionFetcher) ExecutedPosition() (mysql.Position, error) {
+	f.calls++
+	if f.calls >= f.callsUntil {
+		return f.target, nil
+	}
+	return f.behind, nil
+}
+
+func TestWaitForGTIDZeroTargetIsNoOp(t *testing.T) {
+	require.NoError(t, waitForGTID(context.Background(), &advancingPositionFetcher{}, mysql.Position{}, time.Second))
+}
+
+func TestWaitForGTIDTimesOut(t *testing.T) {
+	target, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	require.NoError(t, err)
+	behind, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-2")
+	require.NoError(t, err)
+
+	fetcher := &advancingPositionFetcher{target: target, behind: behind, callsUntil: 1000000}
+	err = waitForGTID(context.Background(), fetcher, target, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not catch up")
+}
+
+func TestWaitForGTIDSucceedsOnceCaughtUp(t *testing.T) {
+	target, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	require.NoError(t, err)
+	behind, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-2")
+	require.NoError(t, err)
+
+	fetcher := &advancingPositionFetcher{target: target, behind: behind, callsUntil: 3}
+	require.NoError(t, waitForGTID(context.Background(), fetcher, target, time.Second))
+	assert.GreaterOrEqual(t, fetcher.calls, 3)
+}
+
+func TestBeginReplicaTransactionDelegatesToWaitForGTID(t *testing.T) {
+	require.NoError(t, BeginReplicaTransaction(context.Background(), &advancingPositionFetcher{}, mysql.Position{}, time.Second))
+}
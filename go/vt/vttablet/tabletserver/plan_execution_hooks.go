@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+)
+
+// planExecutionHooks run, in order, immediately before QueryExecutor
+// executes a plan, with the plan's type and whether this tablet currently
+// holds the master role. A non-nil error aborts execution and is returned
+// to the caller instead. QueryExecutor.execute calls runPlanExecutionHooks
+// with its plan's type and isMaster flag before it executes the plan.
+var planExecutionHooks []func(planType planbuilder.PlanType, isMaster bool) error
+
+func init() {
+	planExecutionHooks = append(planExecutionHooks, checkReadOnly)
+}
+
+// runPlanExecutionHooks runs every registered planExecutionHooks entry,
+// returning the first error encountered, if any.
+func runPlanExecutionHooks(planType planbuilder.PlanType, isMaster bool) error {
+	for _, hook := range planExecutionHooks {
+		if err := hook(planType, isMaster); err != nil {
+			return err
+		}
+	}
+	return nil
+}
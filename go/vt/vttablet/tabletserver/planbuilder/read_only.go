@@ -0,0 +1,36 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+// RequiresWrite reports whether a plan of this type may only be executed
+// against a master tablet. BEGIN on a replica sends `START TRANSACTION READ
+// ONLY` downstream, and the tabletserver planner (tabletserver.checkReadOnly,
+// called right before a plan executes) rejects write plans before they ever
+// reach MySQL, rather than relying on MySQL's own read-only error.
+//
+// PlanType and its individual values (PlanSelect, PlanInsert, PlanDDL, ...)
+// are declared in plan.go; this lives in its own file because it is new
+// behavior layered on an existing classification, not part of how a plan
+// is built.
+func (pt PlanType) RequiresWrite() bool {
+	switch pt {
+	case PlanInsert, PlanInsertMessage, PlanUpsertPk, PlanUpdate, PlanDelete, PlanDDL:
+		return true
+	default:
+		return false
+	}
+}
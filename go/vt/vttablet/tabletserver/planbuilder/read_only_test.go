@@ -0,0 +1,35 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanTypeRequiresWrite(t *testing.T) {
+	writes := []PlanType{PlanInsert, PlanInsertMessage, PlanUpsertPk, PlanUpdate, PlanDelete, PlanDDL}
+	for _, pt := range writes {
+		assert.True(t, pt.RequiresWrite(), "%v should require a master tablet", pt)
+	}
+
+	reads := []PlanType{PlanSelect, PlanSelectImpossible, PlanNextval, PlanOtherRead}
+	for _, pt := range reads {
+		assert.False(t, pt.RequiresWrite(), "%v should not require a master tablet", pt)
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+)
+
+// QueryExecutor runs a single plan against this tablet's connection pool.
+type QueryExecutor struct {
+	plan     *planbuilder.Plan
+	isMaster bool
+}
+
+// execute runs qe.plan, after running every registered planExecutionHooks
+// entry - today that's just checkReadOnly - so a write plan that somehow
+// reaches a replica tablet (VTGate's own planbuilder guard notwithstanding)
+// is rejected here instead of reaching MySQL.
+func (qe *QueryExecutor) execute() (*sqltypes.Result, error) {
+	if err := runPlanExecutionHooks(qe.plan.PlanID, qe.isMaster); err != nil {
+		return nil, err
+	}
+	return qe.plan.Execute()
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+)
+
+func TestQueryExecutorExecuteRejectsWriteOnReplica(t *testing.T) {
+	qe := &QueryExecutor{plan: &planbuilder.Plan{PlanID: planbuilder.PlanInsert}, isMaster: false}
+	_, err := qe.execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed")
+}
+
+func TestQueryExecutorExecuteAllowsSelectOnReplica(t *testing.T) {
+	qe := &QueryExecutor{plan: &planbuilder.Plan{PlanID: planbuilder.PlanSelect}, isMaster: false}
+	_, err := qe.execute()
+	require.NoError(t, err)
+}
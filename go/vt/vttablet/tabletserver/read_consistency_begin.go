@@ -0,0 +1,38 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+)
+
+// BeginReplicaTransaction is the entry point TxEngine.Begin calls once a
+// transaction has started on a non-master connection and the session asked
+// for read_consistency "session" or "bounded" (via its captured target GTID
+// and the mode's timeout, computed by vtgate's TxConn.readConsistencyTarget
+// and passed down over the BeginExecute RPC). It blocks the first statement
+// of the transaction until conn has replicated at least as far as target,
+// or returns the DEADLINE_EXCEEDED timeout error from waitForGTID.
+//
+// If target is the zero Position - i.e. read_consistency is "eventual", or
+// wasn't set - this is a no-op, preserving today's behavior.
+func BeginReplicaTransaction(ctx context.Context, conn positionFetcher, target mysql.Position, timeout time.Duration) error {
+	return waitForGTID(ctx, conn, target, timeout)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+)
+
+// erCantExecuteInReadOnlyTransaction is MySQL's own errno (1792) for
+// "Cannot execute statement in a READ ONLY transaction", SQLSTATE 25006.
+// We reuse it so ErrReadOnlyReplica looks, to a client, like the error
+// MySQL would have given had the statement reached it.
+const erCantExecuteInReadOnlyTransaction = 1792
+
+// sqlStateReadOnlyTransaction is the standard SQLSTATE for the error above.
+const sqlStateReadOnlyTransaction = "25006"
+
+// ErrReadOnlyReplica describes a write plan about to run against a
+// non-master tablet. checkReadOnly returns its message as a *mysql.SQLError
+// with errno 1792 and SQLSTATE 25006. The tabletserver planner classifies
+// every plan with PlanType.RequiresWrite, so this is caught before the
+// query is ever sent to MySQL.
+type ErrReadOnlyReplica struct {
+	PlanType planbuilder.PlanType
+}
+
+func (e *ErrReadOnlyReplica) Error() string {
+	return e.PlanType.String() + " not allowed on a replica: this tablet is read-only"
+}
+
+// checkReadOnly rejects a write plan unless isMaster is true. It is called
+// right before a plan executes, with the tablet's current role.
+func checkReadOnly(planType planbuilder.PlanType, isMaster bool) error {
+	if !planType.RequiresWrite() || isMaster {
+		return nil
+	}
+	err := &ErrReadOnlyReplica{PlanType: planType}
+	return mysql.NewSQLError(erCantExecuteInReadOnlyTransaction, sqlStateReadOnlyTransaction, "%s", err.Error())
+}
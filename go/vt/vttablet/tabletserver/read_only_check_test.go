@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+)
+
+func TestCheckReadOnlyAllowsReadsAndMasterWrites(t *testing.T) {
+	require.NoError(t, checkReadOnly(planbuilder.PlanSelect, false))
+	require.NoError(t, checkReadOnly(planbuilder.PlanInsert, true))
+	require.NoError(t, checkReadOnly(planbuilder.PlanDDL, true))
+}
+
+func TestCheckReadOnlyRejectsWritesOnReplica(t *testing.T) {
+	for _, pt := range []planbuilder.PlanType{planbuilder.PlanInsert, planbuilder.PlanUpdate, planbuilder.PlanDelete, planbuilder.PlanDDL} {
+		err := checkReadOnly(pt, false)
+		require.Error(t, err, "%v should be rejected on a replica", pt)
+
+		sqlErr, ok := err.(*mysql.SQLError)
+		require.True(t, ok, "expected a *mysql.SQLError, got %T", err)
+		assert.Equal(t, erCantExecuteInReadOnlyTransaction, sqlErr.Num)
+		assert.Equal(t, sqlStateReadOnlyTransaction, sqlErr.State)
+	}
+}
+
+func TestRunPlanExecutionHooksRunsCheckReadOnly(t *testing.T) {
+	err := runPlanExecutionHooks(planbuilder.PlanInsert, false)
+	require.Error(t, err)
+
+	require.NoError(t, runPlanExecutionHooks(planbuilder.PlanInsert, true))
+	require.NoError(t, runPlanExecutionHooks(planbuilder.PlanSelect, false))
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// transactionConn is the subset of a pooled MySQL connection TxEngine.Begin
+// needs: enough to issue the begin statement itself and, on a replica, to
+// poll how far it has replicated.
+type transactionConn interface {
+	positionFetcher
+	Exec(ctx context.Context, query string, maxrows int, wantfields bool) (*sqltypes.Result, error)
+}
+
+// TxEngine manages the transactions open against this tablet's MySQL
+// connections.
+type TxEngine struct {
+	isMaster bool
+}
+
+// Begin issues beginSQL - "begin" or, on a replica, "start transaction read
+// only" per planbuilder.BeginQueryForTabletType - on conn, then, if this
+// tablet isn't the master and targetGTID is non-zero, blocks via
+// BeginReplicaTransaction until conn has replicated at least that far or
+// timeout elapses.
+func (te *TxEngine) Begin(ctx context.Context, conn transactionConn, beginSQL string, targetGTID mysql.Position, timeout time.Duration) error {
+	if _, err := conn.Exec(ctx, beginSQL, 1, false); err != nil {
+		return err
+	}
+	if te.isMaster {
+		return nil
+	}
+	return BeginReplicaTransaction(ctx, conn, targetGTID, timeout)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// fakeTransactionConn is an advancingPositionFetcher (see gtid_wait_test.go)
+// that also records the SQL TxEngine.Begin issues, standing in for a pooled
+// MySQL connection.
+type fakeTransactionConn struct {
+	advancingPositionFetcher
+	execs []string
+}
+
+func (f *fakeTransactionConn) Exec(ctx context.Context, query string, maxrows int, wantfields bool) (*sqltypes.Result, error) {
+	f.execs = append(f.execs, query)
+	return &sqltypes.Result{}, nil
+}
+
+func TestTxEngineBeginOnMasterDoesNotWait(t *testing.T) {
+	te := &TxEngine{isMaster: true}
+	conn := &fakeTransactionConn{}
+	require.NoError(t, te.Begin(context.Background(), conn, "begin", mysql.Position{}, time.Second))
+	assert.Equal(t, []string{"begin"}, conn.execs)
+}
+
+func TestTxEngineBeginOnReplicaWaitsForTargetGTID(t *testing.T) {
+	target, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	require.NoError(t, err)
+	behind, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-2")
+	require.NoError(t, err)
+
+	te := &TxEngine{isMaster: false}
+	conn := &fakeTransactionConn{advancingPositionFetcher: advancingPositionFetcher{target: target, behind: behind, callsUntil: 3}}
+	require.NoError(t, te.Begin(context.Background(), conn, "start transaction read only", target, time.Second))
+	assert.Equal(t, []string{"start transaction read only"}, conn.execs)
+	assert.GreaterOrEqual(t, conn.calls, 3)
+}
+
+func TestTxEngineBeginOnReplicaTimesOut(t *testing.T) {
+	target, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	require.NoError(t, err)
+	behind, err := mysql.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-2")
+	require.NoError(t, err)
+
+	te := &TxEngine{isMaster: false}
+	conn := &fakeTransactionConn{advancingPositionFetcher: advancingPositionFetcher{target: target, behind: behind, callsUntil: 1000000}}
+	err = te.Begin(context.Background(), conn, "start transaction read only", target, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not catch up")
+}